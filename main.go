@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v55/github"
 	"golang.org/x/oauth2"
@@ -17,25 +21,158 @@ import (
 const maxRepos = 0 // Change to 0 to scan all repositories
 const enableLimit = maxRepos > 0
 
+// PackageMatch records a single npm package/version found in a dependency
+// tree together with whatever advisories a Matcher found for it.
+type PackageMatch struct {
+	Package    string // "name@version"
+	Advisories []Advisory
+}
+
 type ActionInfo struct {
-	Repos            map[string]struct{}
-	UsesNpm          bool
-	IsInfected       bool
-	InfectedPackages []string
-	Analyzed         bool
+	mu sync.Mutex
+	// Usages maps each consuming repo to the set of workflow file paths in
+	// that repo which reference this action. The workflow path is what
+	// SARIF/JSON reports point at so findings render inline on the file
+	// that pulled the action in.
+	Usages             map[string]map[string]struct{}
+	UsesNpm            bool
+	VulnerablePackages []PackageMatch
+	Analyzed           bool
+
+	// Ref is the @ref portion of the `uses:` reference, e.g. "v4" or a
+	// 40-char commit SHA. RefKind, ResolvedSHA and LatestSHA are filled in
+	// by analyzeActions once the ref has been resolved against GitHub (see
+	// refResolver), just before Analyzed is set.
+	// ResolvedSHA is the commit Ref currently points to (equal to Ref
+	// itself when RefKind is sha); LatestSHA is the action repo's default
+	// branch tip, for comparison against a mutable ref.
+	Ref         string
+	RefKind     RefKind
+	ResolvedSHA string
+	LatestSHA   string
+
+	// ResolutionChains records how this action was reached when it wasn't
+	// used directly by a consuming repo's own workflow, e.g. because it's a
+	// composite action's step or part of a reusable workflow. Each entry is
+	// a chain such as "repoA -> actions/foo@v2 -> some-org/composite@sha".
+	ResolutionChains []string
+}
+
+func (info *ActionInfo) IsVulnerable() bool {
+	return len(info.VulnerablePackages) > 0
+}
+
+func (info *ActionInfo) addUsage(repoName, workflowPath string) {
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	workflows, ok := info.Usages[repoName]
+	if !ok {
+		workflows = make(map[string]struct{})
+		info.Usages[repoName] = workflows
+	}
+	if workflowPath != "" {
+		workflows[workflowPath] = struct{}{}
+	}
+}
+
+// actionRegistry is the concurrency-safe store of every `uses:` reference
+// discovered across the org, keyed by the raw action reference string.
+type actionRegistry struct {
+	mu    sync.Mutex
+	repos map[string]*ActionInfo
+}
+
+func newActionRegistry() *actionRegistry {
+	return &actionRegistry{repos: make(map[string]*ActionInfo)}
+}
+
+func (r *actionRegistry) addUsage(actionUse, repoName, workflowPath string) {
+	info := r.getOrCreate(actionUse)
+	if repoName != "" {
+		info.addUsage(repoName, workflowPath)
+	}
+}
+
+func (r *actionRegistry) getOrCreate(actionUse string) *ActionInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, ok := r.repos[actionUse]
+	if !ok {
+		info = &ActionInfo{Usages: make(map[string]map[string]struct{}), Ref: parseActionRef(actionUse).Ref}
+		r.repos[actionUse] = info
+	}
+	return info
+}
+
+// recordChain attaches a human-readable resolution chain to actionUse's
+// ActionInfo, e.g. ["repoA", "actions/foo@v2", "some-org/composite@sha"],
+// so reports can show why a transitively-used action was scanned.
+func (r *actionRegistry) recordChain(actionUse string, chain []string) {
+	info := r.getOrCreate(actionUse)
+
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	info.ResolutionChains = append(info.ResolutionChains, strings.Join(chain, " -> "))
+}
+
+func (r *actionRegistry) snapshot() map[string]*ActionInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string]*ActionInfo, len(r.repos))
+	for actionUse, info := range r.repos {
+		out[actionUse] = info
+	}
+	return out
 }
 
 func main() {
+	offlineDB := flag.String("offline-db", "", "path to an OSV vulnerability export (zip of per-advisory OSV JSON files) to use instead of the osv.dev API")
+	workers := flag.Int("workers", runtime.NumCPU()*2, "number of repositories/actions to process concurrently")
+	registryURL := flag.String("registry", defaultNpmRegistry, "npm registry to resolve package.json dependencies against")
+	resolveDepth := flag.Int("resolve-depth", 6, "maximum depth to recurse when resolving package.json dependencies")
+	format := flag.String("format", "text", "output format: text, json, or sarif")
+	output := flag.String("output", "", "file to write the json or sarif report to (defaults to stdout; the text format always goes to stdout)")
+	flag.Parse()
+
 	ctx := context.Background()
 	client := createGitHubClient(ctx)
 
 	org := getRequiredEnv("GITHUB_ORG")
 
-	fmt.Printf("Scanning repositories in organization: %s\n", org)
+	matcher := createMatcher(*offlineDB)
+	npm := newNpmResolver(*registryURL, *resolveDepth)
+	metrics := &scanMetrics{}
+	start := time.Now()
+
+	fmt.Printf("Scanning repositories in organization: %s using %d workers\n", org, *workers)
+
+	resolver := newActionResolver(ctx, client, metrics)
+	pins := newRefResolver(ctx, client, metrics)
+	registry := scanRepositories(ctx, client, org, *workers, metrics, resolver)
+	usesRepos := registry.snapshot()
+	analyzeActions(ctx, client, usesRepos, matcher, *workers, metrics, npm, pins)
 
-	usesRepos := scanRepositories(ctx, client, org)
-	analyzeActions(ctx, client, usesRepos)
-	printResults(usesRepos)
+	if err := writeResults(*format, *output, usesRepos); err != nil {
+		log.Fatalf("Error writing results: %v", err)
+	}
+
+	fmt.Printf("\n%s\n", metrics.summary(time.Since(start)))
+}
+
+func createMatcher(offlineDB string) Matcher {
+	if offlineDB == "" {
+		return NewOSVMatcher()
+	}
+
+	fmt.Printf("Loading offline OSV database from %s...\n", offlineDB)
+	matcher, err := NewOSVOfflineMatcher(offlineDB)
+	if err != nil {
+		log.Fatalf("Error loading offline OSV database: %v", err)
+	}
+	return matcher
 }
 
 func createGitHubClient(ctx context.Context) *github.Client {
@@ -55,8 +192,38 @@ func getRequiredEnv(key string) string {
 	return value
 }
 
-func scanRepositories(ctx context.Context, client *github.Client, org string) map[string]*ActionInfo {
-	usesRepos := make(map[string]*ActionInfo)
+// scanRepositories paginates the org's repositories on one goroutine and
+// fans them out to workers worker goroutines that each call
+// processRepository concurrently, recording discovered action usages in
+// the returned registry.
+func scanRepositories(ctx context.Context, client *github.Client, org string, workers int, metrics *scanMetrics, resolver *actionResolver) *actionRegistry {
+	registry := newActionRegistry()
+
+	repoNames := make(chan string)
+	go listRepositories(ctx, client, org, metrics, repoNames)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repoName := range repoNames {
+				start := time.Now()
+				processRepository(ctx, client, org, repoName, registry, metrics, resolver)
+				fmt.Printf("Processed repository %s in %s\n", repoName, time.Since(start))
+			}
+		}()
+	}
+
+	wg.Wait()
+	return registry
+}
+
+// listRepositories pages through the org's repositories and sends their
+// names on repoNames, closing the channel once done or once maxRepos (if
+// set) is reached.
+func listRepositories(ctx context.Context, client *github.Client, org string, metrics *scanMetrics, repoNames chan<- string) {
+	defer close(repoNames)
 
 	opt := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{PerPage: 50},
@@ -65,7 +232,13 @@ func scanRepositories(ctx context.Context, client *github.Client, org string) ma
 	repoCount := 0
 
 	for {
-		repos, resp, err := client.Repositories.ListByOrg(ctx, org, opt)
+		var repos []*github.Repository
+		var resp *github.Response
+		err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+			var e error
+			repos, resp, e = client.Repositories.ListByOrg(ctx, org, opt)
+			return resp, e
+		})
 		if err != nil {
 			log.Fatalf("Error listing repositories: %v", err)
 		}
@@ -73,23 +246,21 @@ func scanRepositories(ctx context.Context, client *github.Client, org string) ma
 		for _, repo := range repos {
 			if shouldStopScanning(repoCount) {
 				fmt.Printf("Reached maximum of %d repositories for testing.\n", maxRepos)
-				return usesRepos
+				return
 			}
 
 			repoName := repo.GetName()
 			logRepoProgress(repoCount, repoName)
 			repoCount++
 
-			processRepository(ctx, client, org, repoName, usesRepos)
+			repoNames <- repoName
 		}
 
 		if shouldStopScanning(repoCount) || resp.NextPage == 0 {
-			break
+			return
 		}
 		opt.Page = resp.NextPage
 	}
-
-	return usesRepos
 }
 
 func shouldStopScanning(repoCount int) bool {
@@ -104,8 +275,14 @@ func logRepoProgress(repoCount int, repoName string) {
 	}
 }
 
-func processRepository(ctx context.Context, client *github.Client, org, repoName string, usesRepos map[string]*ActionInfo) {
-	_, contents, _, err := client.Repositories.GetContents(ctx, org, repoName, ".github/workflows", nil)
+func processRepository(ctx context.Context, client *github.Client, org, repoName string, registry *actionRegistry, metrics *scanMetrics, resolver *actionResolver) {
+	var contents []*github.RepositoryContent
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		_, contents, resp, e = client.Repositories.GetContents(ctx, org, repoName, ".github/workflows", nil)
+		return resp, e
+	})
 	if err != nil {
 		if _, ok := err.(*github.ErrorResponse); ok {
 			return // Directory doesn't exist, skip
@@ -116,7 +293,7 @@ func processRepository(ctx context.Context, client *github.Client, org, repoName
 
 	for _, content := range contents {
 		if isWorkflowFile(content) {
-			processWorkflowFile(ctx, client, org, repoName, content, usesRepos)
+			processWorkflowFile(ctx, client, org, repoName, content, registry, metrics, resolver)
 		}
 	}
 }
@@ -126,8 +303,14 @@ func isWorkflowFile(content *github.RepositoryContent) bool {
 		(strings.HasSuffix(content.GetName(), ".yml") || strings.HasSuffix(content.GetName(), ".yaml"))
 }
 
-func processWorkflowFile(ctx context.Context, client *github.Client, org, repoName string, content *github.RepositoryContent, usesRepos map[string]*ActionInfo) {
-	fileContent, _, _, err := client.Repositories.GetContents(ctx, org, repoName, content.GetPath(), nil)
+func processWorkflowFile(ctx context.Context, client *github.Client, org, repoName string, content *github.RepositoryContent, registry *actionRegistry, metrics *scanMetrics, resolver *actionResolver) {
+	var fileContent *github.RepositoryContent
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		fileContent, _, resp, e = client.Repositories.GetContents(ctx, org, repoName, content.GetPath(), nil)
+		return resp, e
+	})
 	if err != nil {
 		log.Printf("Error getting file %s in %s: %v", content.GetPath(), repoName, err)
 		return
@@ -139,10 +322,10 @@ func processWorkflowFile(ctx context.Context, client *github.Client, org, repoNa
 		return
 	}
 
-	extractActionsFromWorkflow(decodedContent, repoName, usesRepos)
+	extractActionsFromWorkflow(decodedContent, repoName, content.GetPath(), registry, resolver)
 }
 
-func extractActionsFromWorkflow(workflowContent, repoName string, usesRepos map[string]*ActionInfo) {
+func extractActionsFromWorkflow(workflowContent, repoName, workflowPath string, registry *actionRegistry, resolver *actionResolver) {
 	var workflow map[string]interface{}
 	if err := yaml.Unmarshal([]byte(workflowContent), &workflow); err != nil {
 		log.Printf("Error unmarshalling YAML in %s: %v", repoName, err)
@@ -155,16 +338,24 @@ func extractActionsFromWorkflow(workflowContent, repoName string, usesRepos map[
 	}
 
 	for _, job := range jobs {
-		processJobSteps(job, repoName, usesRepos)
+		processJobSteps(job, repoName, workflowPath, registry, resolver)
 	}
 }
 
-func processJobSteps(job interface{}, repoName string, usesRepos map[string]*ActionInfo) {
+func processJobSteps(job interface{}, repoName, workflowPath string, registry *actionRegistry, resolver *actionResolver) {
 	jobMap, ok := job.(map[string]interface{})
 	if !ok {
 		return
 	}
 
+	// A job can itself be a call into a reusable workflow
+	// ("jobs.<id>.uses: owner/repo/.github/workflows/foo.yml@ref") instead
+	// of (or in addition to) having its own steps.
+	if uses, ok := jobMap["uses"].(string); ok {
+		registry.addUsage(uses, repoName, workflowPath)
+		resolver.expand(uses, repoName, registry)
+	}
+
 	steps, ok := jobMap["steps"].([]interface{})
 	if !ok {
 		return
@@ -177,38 +368,54 @@ func processJobSteps(job interface{}, repoName string, usesRepos map[string]*Act
 		}
 
 		if uses, ok := stepMap["uses"].(string); ok {
-			addActionUsage(uses, repoName, usesRepos)
+			registry.addUsage(uses, repoName, workflowPath)
+			resolver.expand(uses, repoName, registry)
 		}
 	}
 }
 
-func addActionUsage(actionUse, repoName string, usesRepos map[string]*ActionInfo) {
-	if usesRepos[actionUse] == nil {
-		usesRepos[actionUse] = &ActionInfo{
-			Repos:    make(map[string]struct{}),
-			Analyzed: false,
-		}
-	}
-	usesRepos[actionUse].Repos[repoName] = struct{}{}
-}
-
-func analyzeActions(ctx context.Context, client *github.Client, usesRepos map[string]*ActionInfo) {
+// analyzeActions fans the unique action references in usesRepos out across
+// workers goroutines, each resolving an action's npm dependencies via
+// analyzeActionDependencies.
+func analyzeActions(ctx context.Context, client *github.Client, usesRepos map[string]*ActionInfo, matcher Matcher, workers int, metrics *scanMetrics, npm *npmResolver, pins *refResolver) {
 	fmt.Println("\nAnalyzing actions...")
 
-	for actionUse, info := range usesRepos {
-		if info.Analyzed {
-			continue
-		}
+	type job struct {
+		actionUse string
+		info      *ActionInfo
+	}
 
-		owner, repo := parseActionReference(actionUse)
-		if owner == "" || repo == "" {
-			continue
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		for actionUse, info := range usesRepos {
+			if info.Analyzed {
+				continue
+			}
+			jobs <- job{actionUse: actionUse, info: info}
 		}
-
-		fmt.Printf("Analyzing %s/%s...\n", owner, repo)
-		analyzeActionDependencies(ctx, client, owner, repo, info)
-		info.Analyzed = true
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				owner, repo := parseActionReference(j.actionUse)
+				if owner == "" || repo == "" {
+					continue
+				}
+
+				fmt.Printf("Analyzing %s/%s...\n", owner, repo)
+				analyzeActionDependencies(ctx, client, owner, repo, j.info, matcher, metrics, npm)
+				j.info.RefKind, j.info.ResolvedSHA, j.info.LatestSHA = pins.resolve(owner, repo, j.info.Ref)
+				j.info.Analyzed = true
+			}
+		}()
 	}
+
+	wg.Wait()
 }
 
 func parseActionReference(actionUse string) (string, string) {
@@ -219,18 +426,24 @@ func parseActionReference(actionUse string) (string, string) {
 	return parts[0], parts[1]
 }
 
-func analyzeActionDependencies(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo) {
+func analyzeActionDependencies(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo, matcher Matcher, metrics *scanMetrics, npm *npmResolver) {
 	// First try package-lock.json for complete dependency tree
-	if analyzePackageLockFile(ctx, client, owner, repo, info) {
+	if analyzePackageLockFile(ctx, client, owner, repo, info, matcher, metrics) {
 		return
 	}
 
 	// Fallback to package.json if lock file doesn't exist
-	analyzePackageJsonFile(ctx, client, owner, repo, info)
+	analyzePackageJsonFile(ctx, client, owner, repo, info, matcher, metrics, npm)
 }
 
-func analyzePackageLockFile(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo) bool {
-	packageLock, _, _, err := client.Repositories.GetContents(ctx, owner, repo, "package-lock.json", nil)
+func analyzePackageLockFile(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo, matcher Matcher, metrics *scanMetrics) bool {
+	var packageLock *github.RepositoryContent
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		packageLock, _, resp, e = client.Repositories.GetContents(ctx, owner, repo, "package-lock.json", nil)
+		return resp, e
+	})
 	if err != nil {
 		return false
 	}
@@ -250,12 +463,18 @@ func analyzePackageLockFile(ctx context.Context, client *github.Client, owner, r
 		return true
 	}
 
-	checkPackagesForInfection(lockJSON, info)
+	checkPackagesForInfection(lockJSON, info, matcher)
 	return true
 }
 
-func analyzePackageJsonFile(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo) {
-	_, _, _, err := client.Repositories.GetContents(ctx, owner, repo, "package.json", nil)
+func analyzePackageJsonFile(ctx context.Context, client *github.Client, owner, repo string, info *ActionInfo, matcher Matcher, metrics *scanMetrics, npm *npmResolver) {
+	var packageJSON *github.RepositoryContent
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		packageJSON, _, resp, e = client.Repositories.GetContents(ctx, owner, repo, "package.json", nil)
+		return resp, e
+	})
 	if err != nil {
 		fmt.Printf("  No package.json or package-lock.json found for %s/%s\n", owner, repo)
 		return
@@ -263,15 +482,34 @@ func analyzePackageJsonFile(ctx context.Context, client *github.Client, owner, r
 
 	info.UsesNpm = true
 	fmt.Printf("  Found package.json (no lock file) for %s/%s\n", owner, repo)
+
+	content, err := packageJSON.GetContent()
+	if err != nil {
+		fmt.Printf("  Error reading package.json content for %s/%s: %v\n", owner, repo, err)
+		return
+	}
+
+	refs := npm.resolvePackageJSON([]byte(content))
+	if found := matchPackages(refs, matcher); len(found) > 0 {
+		info.VulnerablePackages = found
+		fmt.Printf("  ⚠️  VULNERABLE: %d packages affected\n", len(found))
+	}
+}
+
+// PackageRef is an npm package name/version pair pulled from a lock file or
+// resolved from a package.json's dependency ranges.
+type PackageRef struct {
+	Name    string
+	Version string
 }
 
-func checkPackagesForInfection(lockJSON map[string]interface{}, info *ActionInfo) {
+func checkPackagesForInfection(lockJSON map[string]interface{}, info *ActionInfo, matcher Matcher) {
 	packages, exists := lockJSON["packages"].(map[string]interface{})
 	if !exists {
 		return
 	}
 
-	foundInfected := []string{}
+	var refs []PackageRef
 
 	for pkgPath, pkgInfo := range packages {
 		if pkgPath == "" { // Skip root package
@@ -288,19 +526,23 @@ func checkPackagesForInfection(lockJSON map[string]interface{}, info *ActionInfo
 			continue
 		}
 
-		pkgName := extractPackageName(pkgPath)
-		fullPkg := pkgName + "@" + version
+		refs = append(refs, PackageRef{Name: extractPackageName(pkgPath), Version: version})
+	}
 
-		if isInfectedPackage(fullPkg) {
-			foundInfected = append(foundInfected, fullPkg)
-		}
+	if found := matchPackages(refs, matcher); len(found) > 0 {
+		info.VulnerablePackages = found
+		fmt.Printf("  ⚠️  VULNERABLE: %d packages affected\n", len(found))
 	}
+}
 
-	if len(foundInfected) > 0 {
-		info.IsInfected = true
-		info.InfectedPackages = foundInfected
-		fmt.Printf("  ⚠️  INFECTED with %d packages: %v\n", len(foundInfected), foundInfected)
+func matchPackages(refs []PackageRef, matcher Matcher) []PackageMatch {
+	var found []PackageMatch
+	for _, ref := range refs {
+		if advisories := matcher.Match(ref.Name, ref.Version); len(advisories) > 0 {
+			found = append(found, PackageMatch{Package: ref.Name + "@" + ref.Version, Advisories: advisories})
+		}
 	}
+	return found
 }
 
 func extractPackageName(pkgPath string) string {
@@ -322,13 +564,16 @@ func extractPackageName(pkgPath string) string {
 	return pkgName
 }
 
-func isInfectedPackage(fullPkg string) bool {
-	for _, infectedPkg := range infectedPackages {
-		if fullPkg == infectedPkg {
-			return true
-		}
+// driftNote reports whether a mutable ref's resolved commit differs from
+// the action repo's default branch tip, when both are known.
+func driftNote(resolvedSHA, latestSHA string) string {
+	if resolvedSHA == "" || latestSHA == "" {
+		return ""
 	}
-	return false
+	if resolvedSHA == latestSHA {
+		return " (matches the default branch tip)"
+	}
+	return " (differs from the default branch tip)"
 }
 
 func printResults(usesRepos map[string]*ActionInfo) {
@@ -338,17 +583,38 @@ func printResults(usesRepos map[string]*ActionInfo) {
 		fmt.Printf("%s:\n", use)
 		fmt.Printf("  Uses npm: %t\n", info.UsesNpm)
 
-		if info.IsInfected {
-			fmt.Printf("  ⚠️  INFECTED: %t\n", info.IsInfected)
-			fmt.Printf("  Infected packages: %v\n", info.InfectedPackages)
+		if info.RefKind != "" && info.RefKind != RefKindSHA {
+			fmt.Printf("  ⚠️  UNPINNED: ref %q is a %s, not an immutable SHA - currently resolves to %s%s\n",
+				info.Ref, info.RefKind, info.ResolvedSHA, driftNote(info.ResolvedSHA, info.LatestSHA))
+		}
+
+		if info.IsVulnerable() {
+			fmt.Printf("  ⚠️  VULNERABLE: true\n")
+			for _, match := range info.VulnerablePackages {
+				fmt.Printf("    - %s: %v\n", match.Package, match.Advisories)
+			}
 		} else {
-			fmt.Printf("  Infected: %t\n", info.IsInfected)
+			fmt.Printf("  Vulnerable: false\n")
 		}
 
 		fmt.Printf("  Used in repositories:\n")
-		for repo := range info.Repos {
-			fmt.Printf("    - %s\n", repo)
+		for repo, workflows := range info.Usages {
+			if len(workflows) == 0 {
+				fmt.Printf("    - %s\n", repo)
+				continue
+			}
+			for workflowPath := range workflows {
+				fmt.Printf("    - %s (%s)\n", repo, workflowPath)
+			}
 		}
+
+		if len(info.ResolutionChains) > 0 {
+			fmt.Printf("  Reached via:\n")
+			for _, chain := range info.ResolutionChains {
+				fmt.Printf("    - %s\n", chain)
+			}
+		}
+
 		fmt.Println()
 	}
 }
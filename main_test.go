@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+type fakeMatcher struct {
+	infected map[string]struct{}
+}
+
+func (m *fakeMatcher) Match(name, version string) []Advisory {
+	if _, ok := m.infected[name+"@"+version]; ok {
+		return []Advisory{{ID: "GHSA-xxxx", Summary: "malicious code"}}
+	}
+	return nil
+}
+
+func newFakeMatcher(infected ...string) *fakeMatcher {
+	set := make(map[string]struct{}, len(infected))
+	for _, pkg := range infected {
+		set[pkg] = struct{}{}
+	}
+	return &fakeMatcher{infected: set}
+}
+
+func TestMatchPackages(t *testing.T) {
+	matcher := newFakeMatcher("left-pad@1.0.0")
+	refs := []PackageRef{
+		{Name: "left-pad", Version: "1.0.0"},
+		{Name: "left-pad", Version: "2.0.0"},
+		{Name: "right-pad", Version: "1.0.0"},
+	}
+
+	found := matchPackages(refs, matcher)
+	if len(found) != 1 || found[0].Package != "left-pad@1.0.0" {
+		t.Fatalf("matchPackages = %+v, want only left-pad@1.0.0", found)
+	}
+}
+
+func TestCheckPackagesForInfection(t *testing.T) {
+	lockJSON := map[string]interface{}{
+		"packages": map[string]interface{}{
+			"": map[string]interface{}{"version": "0.0.0"}, // root, must be skipped
+			"node_modules/left-pad":                  map[string]interface{}{"version": "1.0.0"},
+			"node_modules/foo/node_modules/left-pad": map[string]interface{}{"version": "1.0.0"},
+		},
+	}
+
+	info := &ActionInfo{Usages: make(map[string]map[string]struct{})}
+	checkPackagesForInfection(lockJSON, info, newFakeMatcher("left-pad@1.0.0"))
+
+	if !info.IsVulnerable() {
+		t.Fatalf("expected info to be flagged vulnerable")
+	}
+	if len(info.VulnerablePackages) != 2 {
+		t.Errorf("VulnerablePackages = %+v, want 2 matches (top-level and nested left-pad)", info.VulnerablePackages)
+	}
+}
+
+func TestExtractPackageName(t *testing.T) {
+	cases := []struct{ path, want string }{
+		{"node_modules/left-pad", "left-pad"},
+		{"node_modules/@scope/pkg", "@scope/pkg"},
+		{"node_modules/foo/node_modules/left-pad", "left-pad"},
+	}
+
+	for _, c := range cases {
+		if got := extractPackageName(c.path); got != c.want {
+			t.Errorf("extractPackageName(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const defaultNpmRegistry = "https://registry.npmjs.org"
+
+// npmRegistryPackage is the subset of a registry.npmjs.org package document
+// we care about: every published version and its own dependencies.
+type npmRegistryPackage struct {
+	Versions map[string]npmRegistryVersion `json:"versions"`
+}
+
+type npmRegistryVersion struct {
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// npmResolver walks a package.json's dependency graph against a live (or
+// mirrored) npm registry when no lock file is checked in, so packages are
+// still reachable for vulnerability matching.
+type npmResolver struct {
+	registry string
+	maxDepth int
+	client   *http.Client
+
+	mu           sync.Mutex
+	packageCache map[string]*npmRegistryPackage // name -> registry doc, nil if unresolvable
+}
+
+func newNpmResolver(registry string, maxDepth int) *npmResolver {
+	return &npmResolver{
+		registry:     registry,
+		maxDepth:     maxDepth,
+		client:       http.DefaultClient,
+		packageCache: make(map[string]*npmRegistryPackage),
+	}
+}
+
+// resolvePackageJSON parses a package.json document and returns the flat,
+// deduplicated set of (name, version) pairs reachable from its direct and
+// dev dependencies. seen only breaks cycles within this one dependency
+// tree - it must not be shared across calls, or a package resolved for one
+// action would be silently dropped from every other action that also
+// depends on it.
+func (r *npmResolver) resolvePackageJSON(content []byte) []PackageRef {
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		fmt.Printf("  Error parsing package.json: %v\n", err)
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+
+	var refs []PackageRef
+	for name, versionRange := range manifest.Dependencies {
+		refs = append(refs, r.resolve(name, versionRange, 0, seen)...)
+	}
+	for name, versionRange := range manifest.DevDependencies {
+		refs = append(refs, r.resolve(name, versionRange, 0, seen)...)
+	}
+	return refs
+}
+
+func (r *npmResolver) resolve(name, versionRange string, depth int, seen map[string]struct{}) []PackageRef {
+	if depth > r.maxDepth {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		// Not a plain semver range (git url, "latest", "workspace:*", ...) -
+		// nothing we can resolve against the registry.
+		return nil
+	}
+
+	meta, ok := r.fetchPackageMeta(name)
+	if !ok {
+		return nil
+	}
+
+	version, versionInfo, ok := highestMatchingVersion(meta, constraint)
+	if !ok {
+		return nil
+	}
+
+	key := name + "@" + version
+	if _, already := seen[key]; already {
+		return nil
+	}
+	seen[key] = struct{}{}
+
+	refs := []PackageRef{{Name: name, Version: version}}
+	for depName, depRange := range versionInfo.Dependencies {
+		refs = append(refs, r.resolve(depName, depRange, depth+1, seen)...)
+	}
+	return refs
+}
+
+func highestMatchingVersion(meta *npmRegistryPackage, constraint *semver.Constraints) (string, npmRegistryVersion, bool) {
+	var best *semver.Version
+	var bestRaw string
+	var bestInfo npmRegistryVersion
+
+	for raw, info := range meta.Versions {
+		v, err := semver.NewVersion(raw)
+		if err != nil {
+			continue // skip non-semver or yanked/malformed version entries
+		}
+		if !constraint.Check(v) {
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			best = v
+			bestRaw = raw
+			bestInfo = info
+		}
+	}
+
+	if best == nil {
+		return "", npmRegistryVersion{}, false
+	}
+	return bestRaw, bestInfo, true
+}
+
+func (r *npmResolver) fetchPackageMeta(name string) (*npmRegistryPackage, bool) {
+	r.mu.Lock()
+	if cached, ok := r.packageCache[name]; ok {
+		r.mu.Unlock()
+		return cached, cached != nil
+	}
+	r.mu.Unlock()
+
+	meta, err := r.fetchFromRegistry(name)
+	if err != nil {
+		fmt.Printf("  Error fetching %s from npm registry: %v\n", name, err)
+	}
+
+	r.mu.Lock()
+	r.packageCache[name] = meta
+	r.mu.Unlock()
+
+	return meta, meta != nil
+}
+
+func (r *npmResolver) fetchFromRegistry(name string) (*npmRegistryPackage, error) {
+	endpoint := strings.TrimRight(r.registry, "/") + "/" + name
+
+	resp, err := r.client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil // unpublished/private package, nothing to resolve
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var meta npmRegistryPackage
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("decoding registry response for %s: %w", name, err)
+	}
+	return &meta, nil
+}
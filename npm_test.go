@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestHighestMatchingVersion(t *testing.T) {
+	meta := &npmRegistryPackage{
+		Versions: map[string]npmRegistryVersion{
+			"1.0.0": {},
+			"1.2.0": {},
+			"1.1.0": {},
+			"2.0.0": {}, // outside the constraint, must not win
+			"bogus": {}, // not valid semver, must be skipped
+		},
+	}
+
+	constraint, err := semver.NewConstraint("^1.0.0")
+	if err != nil {
+		t.Fatalf("NewConstraint: %v", err)
+	}
+
+	version, _, ok := highestMatchingVersion(meta, constraint)
+	if !ok {
+		t.Fatalf("expected a match")
+	}
+	if version != "1.2.0" {
+		t.Errorf("version = %q, want %q", version, "1.2.0")
+	}
+}
+
+func TestHighestMatchingVersionNoMatch(t *testing.T) {
+	meta := &npmRegistryPackage{Versions: map[string]npmRegistryVersion{"1.0.0": {}}}
+
+	constraint, err := semver.NewConstraint("^2.0.0")
+	if err != nil {
+		t.Fatalf("NewConstraint: %v", err)
+	}
+
+	if _, _, ok := highestMatchingVersion(meta, constraint); ok {
+		t.Errorf("expected no match")
+	}
+}
+
+// TestResolvePackageJSONSharedDependency guards against the cycle-guard
+// being scoped to the whole npmResolver instead of a single
+// resolvePackageJSON call: two actions that happen to share a dependency
+// must each see it, regardless of which one is resolved first.
+func TestResolvePackageJSONSharedDependency(t *testing.T) {
+	pkg := npmRegistryPackage{Versions: map[string]npmRegistryVersion{"1.2.3": {}}}
+	body, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	resolver := newNpmResolver(server.URL, 6)
+
+	manifest := []byte(`{"dependencies": {"left-pad": "^1.0.0"}}`)
+
+	first := resolver.resolvePackageJSON(manifest)
+	second := resolver.resolvePackageJSON(manifest)
+
+	if len(first) != 1 || first[0].Name != "left-pad" || first[0].Version != "1.2.3" {
+		t.Fatalf("first resolvePackageJSON = %+v, want [{left-pad 1.2.3}]", first)
+	}
+	if len(second) != 1 || second[0].Name != "left-pad" || second[0].Version != "1.2.3" {
+		t.Fatalf("second resolvePackageJSON = %+v, want [{left-pad 1.2.3}] (dependency dropped by a cross-call cycle guard)", second)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v55/github"
+)
+
+// RefKind classifies the @ref portion of a `uses:` reference.
+type RefKind string
+
+const (
+	RefKindSHA      RefKind = "sha"      // 40-char immutable commit SHA
+	RefKindTag      RefKind = "tag"      // mutable tag, e.g. @v4
+	RefKindBranch   RefKind = "branch"   // mutable branch, e.g. @main
+	RefKindUnpinned RefKind = "unpinned" // no ref, or one that couldn't be resolved
+)
+
+// refResolver determines whether an action is pinned to an immutable commit
+// SHA or a mutable tag/branch, and what the action repo's default branch
+// currently points at, so the report can flag the supply-chain risk of
+// mutable references the way actions/checkout and friends recommend
+// pinning against.
+type refResolver struct {
+	ctx     context.Context
+	client  *github.Client
+	metrics *scanMetrics
+
+	mu               sync.Mutex
+	defaultBranchSHA map[string]string // "owner/repo" -> default branch tip SHA
+}
+
+func newRefResolver(ctx context.Context, client *github.Client, metrics *scanMetrics) *refResolver {
+	return &refResolver{
+		ctx:              ctx,
+		client:           client,
+		metrics:          metrics,
+		defaultBranchSHA: make(map[string]string),
+	}
+}
+
+// resolve classifies ref for owner/repo and returns the commit it currently
+// resolves to alongside the repo's default-branch tip SHA, so callers can
+// tell whether a mutable ref has drifted from the tip it was presumably
+// pinned near.
+func (r *refResolver) resolve(owner, repo, ref string) (RefKind, string, string) {
+	latestSHA := r.latestDefaultBranchSHA(owner, repo)
+
+	if ref == "" {
+		return RefKindUnpinned, "", latestSHA
+	}
+	if isCommitSHA(ref) {
+		return RefKindSHA, ref, latestSHA
+	}
+	if sha, ok := r.resolveRef(owner, repo, "tags/"+ref); ok {
+		return RefKindTag, sha, latestSHA
+	}
+	if sha, ok := r.resolveRef(owner, repo, "heads/"+ref); ok {
+		return RefKindBranch, sha, latestSHA
+	}
+
+	return RefKindUnpinned, "", latestSHA
+}
+
+// resolveRef fetches ref (e.g. "tags/v4" or "heads/main") and returns the
+// SHA it currently points to.
+func (r *refResolver) resolveRef(owner, repo, ref string) (string, bool) {
+	var gitRef *github.Reference
+	err := withRateLimitRetry(r.metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		gitRef, resp, e = r.client.Git.GetRef(r.ctx, owner, repo, ref)
+		return resp, e
+	})
+	if err != nil {
+		return "", false
+	}
+	return gitRef.GetObject().GetSHA(), true
+}
+
+func (r *refResolver) latestDefaultBranchSHA(owner, repo string) string {
+	key := owner + "/" + repo
+
+	r.mu.Lock()
+	if sha, ok := r.defaultBranchSHA[key]; ok {
+		r.mu.Unlock()
+		return sha
+	}
+	r.mu.Unlock()
+
+	sha := r.fetchDefaultBranchSHA(owner, repo)
+
+	r.mu.Lock()
+	r.defaultBranchSHA[key] = sha
+	r.mu.Unlock()
+
+	return sha
+}
+
+func (r *refResolver) fetchDefaultBranchSHA(owner, repo string) string {
+	var repoInfo *github.Repository
+	err := withRateLimitRetry(r.metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		repoInfo, resp, e = r.client.Repositories.Get(r.ctx, owner, repo)
+		return resp, e
+	})
+	if err != nil {
+		return ""
+	}
+
+	defaultBranch := repoInfo.GetDefaultBranch()
+	if defaultBranch == "" {
+		return ""
+	}
+
+	var sha string
+	err = withRateLimitRetry(r.metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		sha, resp, e = r.client.Repositories.GetCommitSHA1(r.ctx, owner, repo, defaultBranch, "")
+		return resp, e
+	})
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+func isCommitSHA(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	for _, c := range ref {
+		isHex := (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+		if !isHex {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestIsCommitSHA(t *testing.T) {
+	cases := []struct {
+		ref  string
+		want bool
+	}{
+		{"8f4b7f84864484a7bf31766abe9204da3cbe65b0", true},
+		{"8F4B7F84864484A7BF31766ABE9204DA3CBE65B0", true},
+		{"v4", false},
+		{"main", false},
+		{"", false},
+		{"8f4b7f84864484a7bf31766abe9204da3cbe65b", false},   // 39 chars
+		{"8f4b7f84864484a7bf31766abe9204da3cbe65b00", false}, // 41 chars
+		{"8f4b7f84864484a7bf31766abe9204da3cbe65bg", false},  // non-hex char
+	}
+
+	for _, c := range cases {
+		if got := isCommitSHA(c.ref); got != c.want {
+			t.Errorf("isCommitSHA(%q) = %t, want %t", c.ref, got, c.want)
+		}
+	}
+}
+
+func TestDriftNote(t *testing.T) {
+	cases := []struct {
+		resolvedSHA, latestSHA, want string
+	}{
+		{"abc", "abc", " (matches the default branch tip)"},
+		{"abc", "def", " (differs from the default branch tip)"},
+		{"", "def", ""},
+		{"abc", "", ""},
+	}
+
+	for _, c := range cases {
+		if got := driftNote(c.resolvedSHA, c.latestSHA); got != c.want {
+			t.Errorf("driftNote(%q, %q) = %q, want %q", c.resolvedSHA, c.latestSHA, got, c.want)
+		}
+	}
+}
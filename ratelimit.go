@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+)
+
+const (
+	initialBackoff = 10 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+	maxRetries     = 8
+)
+
+// scanMetrics tracks API usage across the whole run so a final summary can
+// be printed once scanning and analysis complete.
+type scanMetrics struct {
+	apiCalls      int64
+	rateLimitHits int64
+}
+
+func (m *scanMetrics) recordCall() {
+	atomic.AddInt64(&m.apiCalls, 1)
+}
+
+func (m *scanMetrics) recordRateLimitHit() {
+	atomic.AddInt64(&m.rateLimitHits, 1)
+}
+
+func (m *scanMetrics) summary(elapsed time.Duration) string {
+	return fmt.Sprintf("API calls: %d, rate-limit hits: %d, wall time: %s",
+		atomic.LoadInt64(&m.apiCalls), atomic.LoadInt64(&m.rateLimitHits), elapsed)
+}
+
+// withRateLimitRetry calls fn, and if it fails with a GitHub primary or
+// secondary rate-limit error, waits (honoring resp.Rate.Reset when it's
+// available) and retries, calling fn at most maxRetries times in all, with
+// exponential backoff as a fallback when no explicit reset/retry-after is
+// given.
+func withRateLimitRetry(metrics *scanMetrics, fn func() (*github.Response, error)) error {
+	backoff := initialBackoff
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		_, err := fn()
+		metrics.recordCall()
+		if err == nil {
+			return nil
+		}
+
+		wait, retryable := rateLimitWait(err, backoff)
+		if !retryable {
+			return err
+		}
+
+		metrics.recordRateLimitHit()
+		if attempt+1 == maxRetries {
+			break // no retry follows, so don't wait for one
+		}
+		fmt.Printf("  Rate limited (attempt %d/%d), waiting %s before retry\n", attempt+1, maxRetries, wait)
+		time.Sleep(wait)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts due to rate limiting", maxRetries)
+}
+
+func rateLimitWait(err error, backoff time.Duration) (time.Duration, bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if wait := time.Until(rateLimitErr.Rate.Reset.Time); wait > 0 {
+			return wait, true
+		}
+		return backoff, true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	return 0, false
+}
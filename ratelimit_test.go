@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+)
+
+func TestRateLimitWaitPrimaryRateLimitFutureReset(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	err := &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}}
+
+	wait, retryable := rateLimitWait(err, time.Second)
+	if !retryable {
+		t.Fatalf("expected a primary rate-limit error to be retryable")
+	}
+	if wait <= 0 || wait > time.Hour {
+		t.Errorf("wait = %s, want a positive duration close to an hour", wait)
+	}
+}
+
+func TestRateLimitWaitPrimaryRateLimitPastReset(t *testing.T) {
+	reset := time.Now().Add(-time.Hour)
+	err := &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}}
+
+	wait, retryable := rateLimitWait(err, 5*time.Second)
+	if !retryable {
+		t.Fatalf("expected a primary rate-limit error to be retryable")
+	}
+	if wait != 5*time.Second {
+		t.Errorf("wait = %s, want the fallback backoff (5s) since Reset is already in the past", wait)
+	}
+}
+
+func TestRateLimitWaitAbuseWithRetryAfter(t *testing.T) {
+	retryAfter := 3 * time.Second
+	err := &github.AbuseRateLimitError{RetryAfter: &retryAfter}
+
+	wait, retryable := rateLimitWait(err, time.Second)
+	if !retryable {
+		t.Fatalf("expected a secondary/abuse rate-limit error to be retryable")
+	}
+	if wait != retryAfter {
+		t.Errorf("wait = %s, want RetryAfter (%s)", wait, retryAfter)
+	}
+}
+
+func TestRateLimitWaitAbuseWithoutRetryAfter(t *testing.T) {
+	err := &github.AbuseRateLimitError{RetryAfter: nil}
+
+	wait, retryable := rateLimitWait(err, 7*time.Second)
+	if !retryable {
+		t.Fatalf("expected a secondary/abuse rate-limit error to be retryable")
+	}
+	if wait != 7*time.Second {
+		t.Errorf("wait = %s, want the fallback backoff (7s) since RetryAfter is nil", wait)
+	}
+}
+
+func TestRateLimitWaitNonRateLimitError(t *testing.T) {
+	wait, retryable := rateLimitWait(errors.New("boom"), time.Second)
+	if retryable {
+		t.Errorf("expected a non-rate-limit error not to be retryable")
+	}
+	if wait != 0 {
+		t.Errorf("wait = %s, want 0 for a non-retryable error", wait)
+	}
+}
+
+func TestWithRateLimitRetrySucceedsWithoutRetrying(t *testing.T) {
+	metrics := &scanMetrics{}
+	calls := 0
+
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		calls++
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("withRateLimitRetry returned error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRateLimitRetryReturnsNonRetryableErrorImmediately(t *testing.T) {
+	metrics := &scanMetrics{}
+	calls := 0
+	boom := errors.New("boom")
+
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		calls++
+		return nil, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("withRateLimitRetry error = %v, want %v", err, boom)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (non-retryable errors must not be retried)", calls)
+	}
+}
+
+func TestWithRateLimitRetryGivesUpAfterMaxRetries(t *testing.T) {
+	metrics := &scanMetrics{}
+	calls := 0
+	retryAfter := time.Millisecond
+
+	err := withRateLimitRetry(metrics, func() (*github.Response, error) {
+		calls++
+		return nil, &github.AbuseRateLimitError{RetryAfter: &retryAfter}
+	})
+	if err == nil {
+		t.Fatalf("expected withRateLimitRetry to give up and return an error")
+	}
+	if calls != maxRetries {
+		t.Errorf("calls = %d, want %d", calls, maxRetries)
+	}
+	if got := metrics.rateLimitHits; got != maxRetries {
+		t.Errorf("rateLimitHits = %d, want %d", got, maxRetries)
+	}
+}
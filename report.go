@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const sarifToolName = "scan-npm-vulnerable-actions"
+const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+const sarifVersion = "2.1.0"
+
+// writeResults renders usesRepos in the requested format. The json and
+// sarif formats are written to outputPath, or stdout if outputPath is
+// empty; the text format is always printed to stdout and outputPath is not
+// used for it.
+func writeResults(format, outputPath string, usesRepos map[string]*ActionInfo) error {
+	switch format {
+	case "text":
+		printResults(usesRepos)
+		return nil
+	case "json":
+		return writeEncoded(outputPath, buildFindings(usesRepos))
+	case "sarif":
+		return writeEncoded(outputPath, buildSarifReport(usesRepos))
+	default:
+		return fmt.Errorf("unknown format %q (want text, json, or sarif)", format)
+	}
+}
+
+func writeEncoded(outputPath string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+
+	if outputPath == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("writing report to %s: %w", outputPath, err)
+	}
+	fmt.Printf("Wrote report to %s\n", outputPath)
+	return nil
+}
+
+// JSONFinding is one finding record, suitable for piping into jq or feeding
+// into an issue tracker. Kind distinguishes a vulnerable npm package pulled
+// in by an action ("vulnerable-package") from an action referenced by a
+// mutable tag/branch instead of a pinned SHA ("unpinned-action"); the latter
+// leaves NpmPackage/Version/Advisories empty and sets Severity/RefKind/
+// LatestSHA instead.
+type JSONFinding struct {
+	Kind        string         `json:"kind"`
+	Repo        string         `json:"repo"`
+	Workflow    string         `json:"workflow"`
+	Action      string         `json:"action"`
+	Ref         string         `json:"ref"`
+	RefKind     string         `json:"ref_kind,omitempty"`
+	ResolvedSHA string         `json:"resolved_sha,omitempty"`
+	LatestSHA   string         `json:"latest_sha,omitempty"`
+	Severity    string         `json:"severity,omitempty"`
+	NpmPackage  string         `json:"npm_package,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Advisories  []JSONAdvisory `json:"advisories,omitempty"`
+}
+
+type JSONAdvisory struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	URL      string `json:"url"`
+}
+
+func buildFindings(usesRepos map[string]*ActionInfo) []JSONFinding {
+	var findings []JSONFinding
+	findings = append(findings, buildVulnerablePackageFindings(usesRepos)...)
+	findings = append(findings, buildUnpinnedFindings(usesRepos)...)
+	return findings
+}
+
+func buildVulnerablePackageFindings(usesRepos map[string]*ActionInfo) []JSONFinding {
+	var findings []JSONFinding
+
+	for action, info := range usesRepos {
+		if !info.IsVulnerable() {
+			continue
+		}
+
+		ref := parseActionRef(action).Ref
+		advisories := toJSONAdvisories(info.VulnerablePackages)
+
+		for repoName, workflows := range info.Usages {
+			if len(workflows) == 0 {
+				findings = append(findings, buildFindingsForWorkflow(action, ref, repoName, "", info.VulnerablePackages, advisories)...)
+				continue
+			}
+			for workflowPath := range workflows {
+				findings = append(findings, buildFindingsForWorkflow(action, ref, repoName, workflowPath, info.VulnerablePackages, advisories)...)
+			}
+		}
+	}
+
+	return findings
+}
+
+func buildFindingsForWorkflow(action, ref, repoName, workflowPath string, matches []PackageMatch, advisoriesByPackage map[string][]JSONAdvisory) []JSONFinding {
+	var findings []JSONFinding
+	for _, match := range matches {
+		name, version := splitPackageMatch(match.Package)
+		findings = append(findings, JSONFinding{
+			Kind:       "vulnerable-package",
+			Repo:       repoName,
+			Workflow:   workflowPath,
+			Action:     action,
+			Ref:        ref,
+			NpmPackage: name,
+			Version:    version,
+			Advisories: advisoriesByPackage[match.Package],
+		})
+	}
+	return findings
+}
+
+// buildUnpinnedFindings flags actions referenced by a mutable tag/branch (or
+// an unresolvable/missing ref) rather than an immutable commit SHA.
+// Severity is scaled up when the same action is also known to pull in a
+// vulnerable npm package, since a mutable reference on that action is the
+// exact supply-chain path recent npm-in-actions incidents exploited.
+func buildUnpinnedFindings(usesRepos map[string]*ActionInfo) []JSONFinding {
+	var findings []JSONFinding
+
+	for action, info := range usesRepos {
+		if info.RefKind == "" || info.RefKind == RefKindSHA {
+			continue
+		}
+
+		severity := "warning"
+		if info.IsVulnerable() {
+			severity = "error"
+		}
+
+		for repoName, workflows := range info.Usages {
+			if len(workflows) == 0 {
+				findings = append(findings, buildUnpinnedFinding(action, repoName, "", info, severity))
+				continue
+			}
+			for workflowPath := range workflows {
+				findings = append(findings, buildUnpinnedFinding(action, repoName, workflowPath, info, severity))
+			}
+		}
+	}
+
+	return findings
+}
+
+func buildUnpinnedFinding(action, repoName, workflowPath string, info *ActionInfo, severity string) JSONFinding {
+	return JSONFinding{
+		Kind:        "unpinned-action",
+		Repo:        repoName,
+		Workflow:    workflowPath,
+		Action:      action,
+		Ref:         info.Ref,
+		RefKind:     string(info.RefKind),
+		ResolvedSHA: info.ResolvedSHA,
+		LatestSHA:   info.LatestSHA,
+		Severity:    severity,
+	}
+}
+
+func toJSONAdvisories(matches []PackageMatch) map[string][]JSONAdvisory {
+	out := make(map[string][]JSONAdvisory, len(matches))
+	for _, match := range matches {
+		advisories := make([]JSONAdvisory, 0, len(match.Advisories))
+		for _, adv := range match.Advisories {
+			advisories = append(advisories, JSONAdvisory{ID: adv.ID, Severity: adv.Severity, Summary: adv.Summary, URL: adv.URL})
+		}
+		out[match.Package] = advisories
+	}
+	return out
+}
+
+func sarifLevelFor(severity string) string {
+	if severity == "error" {
+		return "error"
+	}
+	return "warning"
+}
+
+func splitPackageMatch(pkg string) (name, version string) {
+	idx := strings.LastIndex(pkg, "@")
+	if idx <= 0 {
+		return pkg, ""
+	}
+	return pkg[:idx], pkg[idx+1:]
+}
+
+// SARIF 2.1.0 types, kept to the subset GitHub code scanning actually reads.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func buildSarifReport(usesRepos map[string]*ActionInfo) sarifLog {
+	seenRules := make(map[string]struct{})
+	var rules []sarifRule
+	var results []sarifResult
+
+	for _, finding := range buildFindings(usesRepos) {
+		uri := finding.Workflow
+		if uri == "" {
+			uri = finding.Repo
+		}
+
+		switch finding.Kind {
+		case "vulnerable-package":
+			for _, adv := range finding.Advisories {
+				if _, ok := seenRules[adv.ID]; !ok {
+					seenRules[adv.ID] = struct{}{}
+					rules = append(rules, sarifRule{ID: adv.ID, ShortDescription: sarifText{Text: adv.Summary}})
+				}
+
+				results = append(results, sarifResult{
+					RuleID: adv.ID,
+					Level:  "error",
+					Message: sarifText{Text: fmt.Sprintf("%s uses %s, which depends on vulnerable package %s@%s (%s)",
+						finding.Repo, finding.Action, finding.NpmPackage, finding.Version, adv.ID)},
+					Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}},
+				})
+			}
+		case "unpinned-action":
+			const ruleID = "unpinned-action"
+			if _, ok := seenRules[ruleID]; !ok {
+				seenRules[ruleID] = struct{}{}
+				rules = append(rules, sarifRule{ID: ruleID, ShortDescription: sarifText{Text: "Action is referenced by a mutable tag/branch instead of a pinned immutable commit SHA"}})
+			}
+
+			results = append(results, sarifResult{
+				RuleID: ruleID,
+				Level:  sarifLevelFor(finding.Severity),
+				Message: sarifText{Text: fmt.Sprintf("%s uses %s pinned to %s %q, currently resolving to %s%s instead of an immutable SHA",
+					finding.Repo, finding.Action, finding.RefKind, finding.Ref, finding.ResolvedSHA, driftNote(finding.ResolvedSHA, finding.LatestSHA))},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}},
+			})
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: sarifToolName, Rules: rules}},
+			Results: results,
+		}},
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import "testing"
+
+func TestBuildFindingsVulnerablePackage(t *testing.T) {
+	info := &ActionInfo{
+		Usages: map[string]map[string]struct{}{
+			"org/consumer": {".github/workflows/ci.yml": {}},
+		},
+		VulnerablePackages: []PackageMatch{
+			{Package: "left-pad@1.0.0", Advisories: []Advisory{{ID: "GHSA-xxxx", Summary: "malicious code"}}},
+		},
+	}
+	usesRepos := map[string]*ActionInfo{"actions/checkout@v4": info}
+
+	findings := buildFindings(usesRepos)
+	if len(findings) != 1 {
+		t.Fatalf("buildFindings = %d findings, want 1", len(findings))
+	}
+
+	f := findings[0]
+	if f.Kind != "vulnerable-package" || f.NpmPackage != "left-pad" || f.Version != "1.0.0" || f.Workflow != ".github/workflows/ci.yml" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+}
+
+func TestBuildFindingsUnpinnedAction(t *testing.T) {
+	info := &ActionInfo{
+		Usages:      map[string]map[string]struct{}{"org/consumer": {}},
+		Ref:         "v4",
+		RefKind:     RefKindTag,
+		ResolvedSHA: "abc",
+		LatestSHA:   "def",
+	}
+	usesRepos := map[string]*ActionInfo{"actions/checkout@v4": info}
+
+	findings := buildFindings(usesRepos)
+	if len(findings) != 1 {
+		t.Fatalf("buildFindings = %d findings, want 1", len(findings))
+	}
+
+	f := findings[0]
+	if f.Kind != "unpinned-action" || f.Severity != "warning" || f.RefKind != "tag" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+}
+
+func TestBuildFindingsUnpinnedActionSeverityScalesWithVulnerability(t *testing.T) {
+	info := &ActionInfo{
+		Usages:             map[string]map[string]struct{}{"org/consumer": {}},
+		Ref:                "v4",
+		RefKind:            RefKindTag,
+		VulnerablePackages: []PackageMatch{{Package: "left-pad@1.0.0"}},
+	}
+	usesRepos := map[string]*ActionInfo{"actions/checkout@v4": info}
+
+	findings := buildUnpinnedFindings(usesRepos)
+	if len(findings) != 1 || findings[0].Severity != "error" {
+		t.Fatalf("unpinned finding for a vulnerable action = %+v, want severity error", findings)
+	}
+}
+
+func TestBuildFindingsSkipsSHAPinnedActions(t *testing.T) {
+	info := &ActionInfo{
+		Usages:  map[string]map[string]struct{}{"org/consumer": {}},
+		Ref:     "8f4b7f84864484a7bf31766abe9204da3cbe65b0",
+		RefKind: RefKindSHA,
+	}
+	usesRepos := map[string]*ActionInfo{"actions/checkout@8f4b7f84864484a7bf31766abe9204da3cbe65b0": info}
+
+	if findings := buildUnpinnedFindings(usesRepos); len(findings) != 0 {
+		t.Errorf("buildUnpinnedFindings for a SHA-pinned action = %+v, want none", findings)
+	}
+}
+
+func TestBuildSarifReportIncludesBothFindingKinds(t *testing.T) {
+	usesRepos := map[string]*ActionInfo{
+		"actions/checkout@v4": {
+			Usages:  map[string]map[string]struct{}{"org/consumer": {".github/workflows/ci.yml": {}}},
+			Ref:     "v4",
+			RefKind: RefKindTag,
+		},
+		"some-org/action@abc": {
+			Usages:             map[string]map[string]struct{}{"org/consumer": {".github/workflows/ci.yml": {}}},
+			VulnerablePackages: []PackageMatch{{Package: "left-pad@1.0.0", Advisories: []Advisory{{ID: "GHSA-xxxx", Summary: "bad"}}}},
+		},
+	}
+
+	report := buildSarifReport(usesRepos)
+	if len(report.Runs) != 1 {
+		t.Fatalf("report.Runs = %d, want 1", len(report.Runs))
+	}
+	if len(report.Runs[0].Results) != 2 {
+		t.Fatalf("report.Runs[0].Results = %d, want 2", len(report.Runs[0].Results))
+	}
+
+	var sawRule, sawAdvisoryRule bool
+	for _, rule := range report.Runs[0].Tool.Driver.Rules {
+		if rule.ID == "unpinned-action" {
+			sawRule = true
+		}
+		if rule.ID == "GHSA-xxxx" {
+			sawAdvisoryRule = true
+		}
+	}
+	if !sawRule || !sawAdvisoryRule {
+		t.Errorf("rules = %+v, want both unpinned-action and GHSA-xxxx", report.Runs[0].Tool.Driver.Rules)
+	}
+}
+
+func TestSplitPackageMatch(t *testing.T) {
+	cases := []struct {
+		pkg, name, version string
+	}{
+		{"left-pad@1.0.0", "left-pad", "1.0.0"},
+		{"@scope/pkg@2.0.0", "@scope/pkg", "2.0.0"},
+		{"no-version", "no-version", ""},
+	}
+
+	for _, c := range cases {
+		name, version := splitPackageMatch(c.pkg)
+		if name != c.name || version != c.version {
+			t.Errorf("splitPackageMatch(%q) = (%q, %q), want (%q, %q)", c.pkg, name, version, c.name, c.version)
+		}
+	}
+}
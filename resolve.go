@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v55/github"
+	"gopkg.in/yaml.v3"
+)
+
+// actionRef is a parsed `uses:` reference. Path is whatever follows
+// owner/repo, and is only set for references into a subdirectory of the
+// repo, e.g. an action in a subfolder ("init") or a reusable workflow
+// (".github/workflows/foo.yml").
+type actionRef struct {
+	Owner string
+	Repo  string
+	Path  string
+	Ref   string
+}
+
+func (ar actionRef) valid() bool {
+	return ar.Owner != "" && ar.Repo != ""
+}
+
+func (ar actionRef) visitKey() string {
+	return ar.Owner + "/" + ar.Repo + "/" + ar.Path + "@" + ar.Ref
+}
+
+func parseActionRef(uses string) actionRef {
+	usePart, ref, _ := strings.Cut(uses, "@")
+
+	parts := strings.SplitN(usePart, "/", 3)
+	if len(parts) < 2 {
+		return actionRef{}
+	}
+
+	ar := actionRef{Owner: parts[0], Repo: parts[1], Ref: ref}
+	if len(parts) == 3 {
+		ar.Path = parts[2]
+	}
+	return ar
+}
+
+func (ar actionRef) isReusableWorkflow() bool {
+	return strings.Contains(ar.Path, ".github/workflows/")
+}
+
+// actionResolver recursively walks composite actions and reusable
+// workflows so that npm packages pulled in transitively (rather than via a
+// top-level `uses:` in the consuming repo's own workflow) still surface in
+// the report.
+type actionResolver struct {
+	ctx     context.Context
+	client  *github.Client
+	metrics *scanMetrics
+
+	visited sync.Map // visitKey() -> struct{}
+}
+
+func newActionResolver(ctx context.Context, client *github.Client, metrics *scanMetrics) *actionResolver {
+	return &actionResolver{ctx: ctx, client: client, metrics: metrics}
+}
+
+// expand registers every action transitively reachable from uses (composite
+// action steps, and reusable workflow jobs) in registry, recording the
+// chain of `uses:` references that led to each one.
+func (r *actionResolver) expand(uses, originRepo string, registry *actionRegistry) {
+	ar := parseActionRef(uses)
+	if !ar.valid() {
+		return
+	}
+	r.walk(ar, []string{originRepo, uses}, registry)
+}
+
+func (r *actionResolver) walk(ar actionRef, chain []string, registry *actionRegistry) {
+	if _, loaded := r.visited.LoadOrStore(ar.visitKey(), struct{}{}); loaded {
+		return
+	}
+
+	if ar.isReusableWorkflow() {
+		r.walkReusableWorkflow(ar, chain, registry)
+		return
+	}
+
+	r.walkCompositeAction(ar, chain, registry)
+}
+
+func (r *actionResolver) walkCompositeAction(ar actionRef, chain []string, registry *actionRegistry) {
+	manifest, ok := r.fetchActionManifest(ar)
+	if !ok {
+		return
+	}
+
+	runs, ok := manifest["runs"].(map[string]interface{})
+	if !ok || runs["using"] != "composite" {
+		return
+	}
+
+	steps, ok := runs["steps"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, step := range steps {
+		r.walkStep(step, chain, registry)
+	}
+}
+
+func (r *actionResolver) walkReusableWorkflow(ar actionRef, chain []string, registry *actionRegistry) {
+	content, ok := r.fetchFileContent(ar.Owner, ar.Repo, ar.Path, ar.Ref)
+	if !ok {
+		return
+	}
+
+	var workflow map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &workflow); err != nil {
+		return
+	}
+
+	jobs, ok := workflow["jobs"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, job := range jobs {
+		jobMap, ok := job.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// A job can itself be a call into another reusable workflow,
+		// instead of (or in addition to) having its own steps.
+		if nestedUses, ok := jobMap["uses"].(string); ok {
+			r.walkUses(nestedUses, chain, registry)
+		}
+
+		steps, ok := jobMap["steps"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, step := range steps {
+			r.walkStep(step, chain, registry)
+		}
+	}
+}
+
+func (r *actionResolver) walkStep(step interface{}, chain []string, registry *actionRegistry) {
+	stepMap, ok := step.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	nestedUses, ok := stepMap["uses"].(string)
+	if !ok {
+		return
+	}
+
+	r.walkUses(nestedUses, chain, registry)
+}
+
+func (r *actionResolver) walkUses(nestedUses string, chain []string, registry *actionRegistry) {
+	nestedChain := append(append([]string{}, chain...), nestedUses)
+
+	registry.addUsage(nestedUses, "", "")
+	registry.recordChain(nestedUses, nestedChain)
+
+	r.walk(parseActionRef(nestedUses), nestedChain, registry)
+}
+
+// fetchActionManifest fetches action.yml (falling back to action.yaml) for
+// ar at its pinned ref.
+func (r *actionResolver) fetchActionManifest(ar actionRef) (map[string]interface{}, bool) {
+	for _, name := range []string{"action.yml", "action.yaml"} {
+		if ar.Path != "" {
+			name = ar.Path + "/" + name
+		}
+		content, ok := r.fetchFileContent(ar.Owner, ar.Repo, name, ar.Ref)
+		if !ok {
+			continue
+		}
+
+		var manifest map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &manifest); err != nil {
+			continue
+		}
+		return manifest, true
+	}
+	return nil, false
+}
+
+func (r *actionResolver) fetchFileContent(owner, repo, path, ref string) (string, bool) {
+	var fileContent *github.RepositoryContent
+	err := withRateLimitRetry(r.metrics, func() (*github.Response, error) {
+		var resp *github.Response
+		var e error
+		fileContent, _, resp, e = r.client.Repositories.GetContents(r.ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		return resp, e
+	})
+	if err != nil {
+		return "", false
+	}
+
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return "", false
+	}
+	return content, true
+}
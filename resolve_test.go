@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+)
+
+// newTestGitHubClient returns a github.Client whose API calls are served by
+// handler instead of the real github.com, so resolver tests can run without
+// network access.
+func newTestGitHubClient(t *testing.T, handler http.Handler) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+	client.BaseURL = baseURL
+
+	return client
+}
+
+// fileHandler serves the repository-contents API for a fixed map of
+// "owner/repo/path" to file content.
+func fileHandler(t *testing.T, files map[string]string) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, content := range files {
+			if r.URL.Path == "/repos/"+key {
+				body, err := json.Marshal(map[string]interface{}{
+					"type":     "file",
+					"encoding": "base64",
+					"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+					"path":     key,
+				})
+				if err != nil {
+					t.Fatalf("marshalling fixture for %s: %v", key, err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(body)
+				return
+			}
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+}
+
+func TestProcessJobStepsRegistersJobLevelReusableWorkflow(t *testing.T) {
+	workflow := `
+jobs:
+  call-shared:
+    uses: some-org/shared/.github/workflows/ci.yml@v1
+`
+	registry := newActionRegistry()
+	metrics := &scanMetrics{}
+	client := newTestGitHubClient(t, http.NotFoundHandler())
+	resolver := newActionResolver(context.Background(), client, metrics)
+
+	extractActionsFromWorkflow(workflow, "consumer-repo", ".github/workflows/ci.yml", registry, resolver)
+
+	usesRepos := registry.snapshot()
+	info, ok := usesRepos["some-org/shared/.github/workflows/ci.yml@v1"]
+	if !ok {
+		t.Fatalf("job-level reusable workflow call was not registered; got %+v", usesRepos)
+	}
+	if _, used := info.Usages["consumer-repo"]; !used {
+		t.Errorf("expected consumer-repo to be recorded as a user of the reusable workflow, got %+v", info.Usages)
+	}
+}
+
+func TestActionResolverExpandWalksCompositeAction(t *testing.T) {
+	actionYAML := `
+runs:
+  using: composite
+  steps:
+    - uses: actions/checkout@v4
+`
+	client := newTestGitHubClient(t, fileHandler(t, map[string]string{
+		"some-org/composite-action/contents/action.yml": actionYAML,
+	}))
+
+	metrics := &scanMetrics{}
+	resolver := newActionResolver(context.Background(), client, metrics)
+	registry := newActionRegistry()
+
+	resolver.expand("some-org/composite-action@v1", "consumer-repo", registry)
+
+	usesRepos := registry.snapshot()
+	if _, ok := usesRepos["actions/checkout@v4"]; !ok {
+		t.Fatalf("expected the composite action's nested step to be discovered, got %+v", usesRepos)
+	}
+	if len(usesRepos["actions/checkout@v4"].ResolutionChains) == 0 {
+		t.Errorf("expected a resolution chain recording how actions/checkout@v4 was reached")
+	}
+}
+
+func TestActionResolverExpandWalksReusableWorkflowJobLevelUses(t *testing.T) {
+	topWorkflow := `
+jobs:
+  build:
+    uses: some-org/shared/.github/workflows/inner.yml@v1
+`
+	innerWorkflow := `
+jobs:
+  test:
+    steps:
+      - uses: actions/setup-node@v4
+`
+	client := newTestGitHubClient(t, fileHandler(t, map[string]string{
+		"some-org/shared/contents/.github/workflows/ci.yml":    topWorkflow,
+		"some-org/shared/contents/.github/workflows/inner.yml": innerWorkflow,
+	}))
+
+	metrics := &scanMetrics{}
+	resolver := newActionResolver(context.Background(), client, metrics)
+	registry := newActionRegistry()
+
+	resolver.expand("some-org/shared/.github/workflows/ci.yml@v1", "consumer-repo", registry)
+
+	usesRepos := registry.snapshot()
+	if _, ok := usesRepos["some-org/shared/.github/workflows/inner.yml@v1"]; !ok {
+		t.Fatalf("expected the job-level reusable workflow call inside the reusable workflow to be discovered, got %+v", usesRepos)
+	}
+	if _, ok := usesRepos["actions/setup-node@v4"]; !ok {
+		t.Fatalf("expected the nested reusable workflow's own step to be discovered, got %+v", usesRepos)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Advisory describes a single known vulnerability matched against a
+// package/version pair, as reported by a Matcher backend.
+type Advisory struct {
+	ID       string // e.g. GHSA-xxxx-xxxx-xxxx or CVE-2024-12345
+	Severity string
+	Summary  string
+	URL      string
+}
+
+// Matcher looks up known vulnerabilities for a given npm package/version.
+// Implementations may hit a static list, the OSV API, a local OSV export,
+// or any other advisory source.
+type Matcher interface {
+	Match(name, version string) []Advisory
+}
+
+// osvQueryResult mirrors the subset of osv.dev's POST /v1/query response we
+// care about.
+type osvQueryResult struct {
+	Vulns []osvVuln `json:"vulns"`
+}
+
+type osvVuln struct {
+	ID       string         `json:"id"`
+	Summary  string         `json:"summary"`
+	Severity []osvSeverity  `json:"severity"`
+	Aliases  []string       `json:"aliases"`
+	Refs     []osvReference `json:"references"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvReference struct {
+	URL string `json:"url"`
+}
+
+func (v osvVuln) toAdvisory() Advisory {
+	severity := "unknown"
+	if len(v.Severity) > 0 {
+		severity = v.Severity[0].Score
+	}
+	url := ""
+	if len(v.Refs) > 0 {
+		url = v.Refs[0].URL
+	}
+	return Advisory{ID: v.ID, Severity: severity, Summary: v.Summary, URL: url}
+}
+
+// OSVMatcher queries the public OSV database over HTTP. Results are cached
+// in-process per (name, version) so a package that shows up in dozens of
+// lock files is only ever looked up once.
+type OSVMatcher struct {
+	endpoint string
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[string][]Advisory
+}
+
+const osvQueryEndpoint = "https://api.osv.dev/v1/query"
+
+func NewOSVMatcher() *OSVMatcher {
+	return &OSVMatcher{
+		endpoint: osvQueryEndpoint,
+		client:   http.DefaultClient,
+		cache:    make(map[string][]Advisory),
+	}
+}
+
+func (m *OSVMatcher) Match(name, version string) []Advisory {
+	key := name + "@" + version
+
+	m.mu.Lock()
+	if cached, ok := m.cache[key]; ok {
+		m.mu.Unlock()
+		return cached
+	}
+	m.mu.Unlock()
+
+	advisories, err := m.query(name, version)
+	if err != nil {
+		fmt.Printf("  Error querying OSV for %s: %v\n", key, err)
+		advisories = nil
+	}
+
+	m.mu.Lock()
+	m.cache[key] = advisories
+	m.mu.Unlock()
+
+	return advisories
+}
+
+func (m *OSVMatcher) query(name, version string) ([]Advisory, error) {
+	body := map[string]interface{}{
+		"package": map[string]string{
+			"name":      name,
+			"ecosystem": "npm",
+		},
+		"version": version,
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling OSV query: %w", err)
+	}
+
+	resp, err := m.client.Post(m.endpoint, "application/json", strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, fmt.Errorf("calling OSV: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV returned status %d", resp.StatusCode)
+	}
+
+	var result osvQueryResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding OSV response: %w", err)
+	}
+
+	advisories := make([]Advisory, 0, len(result.Vulns))
+	for _, vuln := range result.Vulns {
+		advisories = append(advisories, vuln.toAdvisory())
+	}
+	return advisories, nil
+}
+
+// offlineEntry pairs a vulnerability with the specific versions of a
+// package it affects, as recorded in the OSV export.
+type offlineEntry struct {
+	vuln     osvVuln
+	versions map[string]struct{}
+}
+
+// OSVOfflineMatcher loads an OSV vulnerability export (a zip holding one OSV
+// JSON record per advisory, as published at https://osv-vulnerabilities.storage.googleapis.com/npm/all.zip)
+// so scans can run without network access.
+type OSVOfflineMatcher struct {
+	byPackage map[string][]offlineEntry
+}
+
+func NewOSVOfflineMatcher(path string) (*OSVOfflineMatcher, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening offline OSV db %s: %w", path, err)
+	}
+	defer reader.Close()
+
+	m := &OSVOfflineMatcher{byPackage: make(map[string][]offlineEntry)}
+
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening %s in offline OSV db: %w", file.Name, err)
+		}
+
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s in offline OSV db: %w", file.Name, err)
+		}
+
+		var entry struct {
+			osvVuln
+			Affected []struct {
+				Package struct {
+					Name string `json:"name"`
+				} `json:"package"`
+				Versions []string `json:"versions"`
+			} `json:"affected"`
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue // skip malformed entries rather than aborting the whole load
+		}
+
+		for _, affected := range entry.Affected {
+			versions := make(map[string]struct{}, len(affected.Versions))
+			for _, v := range affected.Versions {
+				versions[v] = struct{}{}
+			}
+			m.byPackage[affected.Package.Name] = append(m.byPackage[affected.Package.Name], offlineEntry{
+				vuln:     entry.osvVuln,
+				versions: versions,
+			})
+		}
+	}
+
+	return m, nil
+}
+
+func (m *OSVOfflineMatcher) Match(name, version string) []Advisory {
+	var advisories []Advisory
+	for _, entry := range m.byPackage[name] {
+		if _, ok := entry.versions[version]; ok {
+			advisories = append(advisories, entry.vuln.toAdvisory())
+		}
+	}
+	return advisories
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeOfflineDB(t *testing.T, entries map[string]interface{}) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "osv.zip")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			t.Fatalf("marshal entry %s: %v", name, err)
+		}
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("create %s in zip: %v", name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			t.Fatalf("write %s in zip: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	return path
+}
+
+func TestOSVOfflineMatcherFiltersByVersion(t *testing.T) {
+	path := writeOfflineDB(t, map[string]interface{}{
+		"GHSA-xxxx.json": map[string]interface{}{
+			"id":      "GHSA-xxxx-xxxx-xxxx",
+			"summary": "malicious code in left-pad",
+			"affected": []map[string]interface{}{
+				{
+					"package":  map[string]string{"name": "left-pad"},
+					"versions": []string{"1.0.0"},
+				},
+			},
+		},
+	})
+
+	matcher, err := NewOSVOfflineMatcher(path)
+	if err != nil {
+		t.Fatalf("NewOSVOfflineMatcher: %v", err)
+	}
+
+	if advisories := matcher.Match("left-pad", "1.0.0"); len(advisories) != 1 {
+		t.Errorf("Match(left-pad, 1.0.0) = %v, want 1 advisory", advisories)
+	}
+
+	// A different, unaffected version of the same package must not match -
+	// the whole point of recording versions per advisory.
+	if advisories := matcher.Match("left-pad", "2.0.0"); len(advisories) != 0 {
+		t.Errorf("Match(left-pad, 2.0.0) = %v, want no advisories", advisories)
+	}
+
+	if advisories := matcher.Match("right-pad", "1.0.0"); len(advisories) != 0 {
+		t.Errorf("Match(right-pad, 1.0.0) = %v, want no advisories", advisories)
+	}
+}
+
+func TestOSVOfflineMatcherSkipsNonJSONAndMalformedEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "osv.zip")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	if fw, err := w.Create("README.txt"); err != nil {
+		t.Fatalf("create README.txt: %v", err)
+	} else {
+		fw.Write([]byte("not json"))
+	}
+	if fw, err := w.Create("broken.json"); err != nil {
+		t.Fatalf("create broken.json: %v", err)
+	} else {
+		fw.Write([]byte("{not valid json"))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	matcher, err := NewOSVOfflineMatcher(path)
+	if err != nil {
+		t.Fatalf("NewOSVOfflineMatcher: %v", err)
+	}
+	if advisories := matcher.Match("anything", "1.0.0"); len(advisories) != 0 {
+		t.Errorf("Match on an empty db = %v, want no advisories", advisories)
+	}
+}